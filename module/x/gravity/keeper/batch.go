@@ -1,9 +1,6 @@
 package keeper
 
 import (
-	"fmt"
-	"strconv"
-
 	"github.com/cosmos/cosmos-sdk/store/prefix"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -16,8 +13,8 @@ const BatchTxSize = 100
 // BuildBatchTx starts the following process chain:
 // - find bridged denominator for given voucher type
 // - determine if a an unexecuted batch is already waiting for this token type, if so confirm the new batch would
-//   have a higher total fees. If not exit withtout creating a batch
-// - select available transactions from the sendToEthereum transaction pool sorted by fee desc
+//   score at least as high under the configured BatchSelector. If not exit withtout creating a batch
+// - select available transactions from the sendToEthereum transaction pool using the configured BatchSelector
 // - persist an batch tx object with an incrementing ID = nonce
 // - emit an event
 func (k Keeper) BuildBatchTx(
@@ -28,25 +25,37 @@ func (k Keeper) BuildBatchTx(
 		return nil, sdkerrors.Wrap(types.ErrInvalid, "max elements value")
 	}
 
-	lastBatch := k.GetLastBatchTxByTokenType(ctx, contractAddress)
+	// a bridge migration sweeping this token contract's balance to a new Safe is in flight,
+	// refuse to build ordinary batches against it until the migration batch clears
+	if k.GetMigrationInProgress(ctx, contractAddress) {
+		return nil, sdkerrors.Wrap(types.ErrInvalid, "migration in progress for token contract")
+	}
+
+	selector := k.batchSelectorByStrategy(ctx)
+
+	// the profitability check below is the hot path this runs on every attempted batch build,
+	// so it goes through the single-entry LatestBatchNonceByToken key rather than
+	// GetLastBatchTxByTokenType's reverse iterator
+	var lastBatch *types.BatchTx
+	if lastNonce, ok := k.getLatestBatchNonceByToken(ctx, contractAddress); ok {
+		lastBatch = k.GetBatchTx(ctx, contractAddress, lastNonce)
+	}
 
 	// lastBatch may be nil if there are no existing batches, we only need
 	// to perform this check if a previous batch exists
 	if lastBatch != nil {
 		// this traverses the current tx pool for this token type and determines what
-		// fees a hypothetical batch would have if created
-		currentFees := k.GetBatchFeesByTokenType(ctx, contractAddress)
-		if currentFees == nil {
-			return nil, sdkerrors.Wrap(types.ErrInvalid, "error getting fees from tx pool")
-		}
+		// a hypothetical batch would score if created
+		currentPool := k.getUnbatchedTxPool(ctx, contractAddress)
 
-		lastFees := lastBatch.GetFees()
-		if lastFees.GT(currentFees.TotalFees) {
-			return nil, sdkerrors.Wrap(types.ErrInvalid, "new batch would not be more profitable")
+		lastScore := selector.Score(lastBatch.Transactions)
+		currentScore := selector.Score(currentPool)
+		if lastScore.GT(currentScore) {
+			return nil, sdkerrors.Wrap(types.ErrInvalid, "new batch would not score higher than the outstanding one")
 		}
 	}
 
-	selectedTx, err := k.pickUnbatchedTX(ctx, contractAddress, maxElements)
+	selectedTx, err := selector.Select(ctx, k, contractAddress, maxElements)
 	if len(selectedTx) == 0 || err != nil {
 		return nil, err
 	}
@@ -63,15 +72,7 @@ func (k Keeper) BuildBatchTx(
 	checkpoint := batch.GetCheckpoint(k.GetGravityID(ctx))
 	k.SetPastEthSignatureCheckpoint(ctx, checkpoint)
 
-	batchEvent := sdk.NewEvent(
-		types.EventTypeBatchTx,
-		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
-		sdk.NewAttribute(types.AttributeKeyContract, k.GetBridgeContractAddress(ctx)),
-		sdk.NewAttribute(types.AttributeKeyBridgeChainID, strconv.Itoa(int(k.GetBridgeChainID(ctx)))),
-		sdk.NewAttribute(types.AttributeKeyBatchTxID, fmt.Sprint(nextID)),
-		sdk.NewAttribute(types.AttributeKeyNonce, fmt.Sprint(nextID)),
-	)
-	ctx.EventManager().EmitEvent(batchEvent)
+	k.emitBatchTxCreatedEvent(ctx, batch)
 	return batch, nil
 }
 
@@ -117,6 +118,8 @@ func (k Keeper) BatchTxExecuted(ctx sdk.Context, tokenContract string, nonce uin
 		return false
 	})
 
+	k.emitBatchTxExecutedEvent(ctx, b)
+
 	// Delete batch since it is finished
 	k.DeleteBatch(ctx, *b)
 
@@ -128,21 +131,41 @@ func (k Keeper) StoreBatch(ctx sdk.Context, batch *types.BatchTx) {
 	store := ctx.KVStore(k.storeKey)
 	// set the current block height when storing the batch
 	batch.Block = uint64(ctx.BlockHeight())
+	bz := k.cdc.MustMarshalBinaryBare(batch)
+
 	key := types.GetBatchTxKey(batch.TokenContract, batch.BatchNonce)
-	store.Set(key, k.cdc.MustMarshalBinaryBare(batch))
+	store.Set(key, bz)
 
 	blockKey := types.GetBatchTxBlockKey(batch.Block)
-	store.Set(blockKey, k.cdc.MustMarshalBinaryBare(batch))
+	store.Set(blockKey, bz)
+
+	k.indexBatch(ctx, batch, bz)
 }
 
 // StoreBatchUnsafe stores a transaction batch w/o setting the height
 func (k Keeper) StoreBatchUnsafe(ctx sdk.Context, batch *types.BatchTx) {
 	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshalBinaryBare(batch)
+
 	key := types.GetBatchTxKey(batch.TokenContract, batch.BatchNonce)
-	store.Set(key, k.cdc.MustMarshalBinaryBare(batch))
+	store.Set(key, bz)
 
 	blockKey := types.GetBatchTxBlockKey(batch.Block)
-	store.Set(blockKey, k.cdc.MustMarshalBinaryBare(batch))
+	store.Set(blockKey, bz)
+
+	k.indexBatch(ctx, batch, bz)
+}
+
+// indexBatch maintains the per-token secondary index and "latest nonce" pointer that
+// GetLastBatchTxByTokenType and BuildBatchTx's profitability check rely on, so neither has to
+// scan every batch ever stored across every token.
+func (k Keeper) indexBatch(ctx sdk.Context, batch *types.BatchTx, bz []byte) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetBatchTxByTokenNonceKey(batch.TokenContract, batch.BatchNonce), bz)
+
+	if latest, ok := k.getLatestBatchNonceByToken(ctx, batch.TokenContract); !ok || batch.BatchNonce > latest {
+		store.Set(types.GetLatestBatchNonceByTokenKey(batch.TokenContract), types.UInt64Bytes(batch.BatchNonce))
+	}
 }
 
 // DeleteBatch deletes an batch tx
@@ -150,25 +173,19 @@ func (k Keeper) DeleteBatch(ctx sdk.Context, batch types.BatchTx) {
 	store := ctx.KVStore(k.storeKey)
 	store.Delete(types.GetBatchTxKey(batch.TokenContract, batch.BatchNonce))
 	store.Delete(types.GetBatchTxBlockKey(batch.Block))
-}
-
-// pickUnbatchedTX find TX in pool and remove from "available" second index
-func (k Keeper) pickUnbatchedTX(
-	ctx sdk.Context,
-	contractAddress string,
-	maxElements int) ([]*types.SendToEthereum, error) {
-	var selectedTx []*types.SendToEthereum
-	var err error
-	k.IterateSendToEthereumPoolByFee(ctx, contractAddress, func(txID uint64, tx *types.SendToEthereum) bool {
-		if tx != nil && tx.Fee != nil {
-			selectedTx = append(selectedTx, tx)
-			err = k.removeFromUnbatchedTXIndex(ctx, *tx.Fee, txID)
-			return err != nil || len(selectedTx) == maxElements
+	store.Delete(types.GetBatchTxByTokenNonceKey(batch.TokenContract, batch.BatchNonce))
+
+	// only move the "latest" pointer if this batch is in fact the latest one on record for
+	// the token, otherwise canceling an older batch would wrongly erase the pointer to a
+	// newer one that is still outstanding; when it is, the pointer falls back to the newest
+	// batch still left in the per-token index, or is cleared if none is left
+	if latest, ok := k.getLatestBatchNonceByToken(ctx, batch.TokenContract); ok && latest == batch.BatchNonce {
+		if prev := k.GetLastBatchTxByTokenType(ctx, batch.TokenContract); prev != nil {
+			store.Set(types.GetLatestBatchNonceByTokenKey(batch.TokenContract), types.UInt64Bytes(prev.BatchNonce))
+		} else {
+			store.Delete(types.GetLatestBatchNonceByTokenKey(batch.TokenContract))
 		}
-
-		return true
-	})
-	return selectedTx, err
+	}
 }
 
 // GetBatchTx loads a batch object. Returns nil when not exists.
@@ -202,15 +219,36 @@ func (k Keeper) CancelBatchTx(ctx sdk.Context, tokenContract string, nonce uint6
 	// Delete batch since it is finished
 	k.DeleteBatch(ctx, *batch)
 
-	batchEvent := sdk.NewEvent(
-		types.EventTypeBatchTxCanceled,
-		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
-		sdk.NewAttribute(types.AttributeKeyContract, k.GetBridgeContractAddress(ctx)),
-		sdk.NewAttribute(types.AttributeKeyBridgeChainID, strconv.Itoa(int(k.GetBridgeChainID(ctx)))),
-		sdk.NewAttribute(types.AttributeKeyBatchTxID, fmt.Sprint(nonce)),
-		sdk.NewAttribute(types.AttributeKeyNonce, fmt.Sprint(nonce)),
-	)
-	ctx.EventManager().EmitEvent(batchEvent)
+	k.emitBatchTxCanceledEvent(ctx, tokenContract, nonce)
+	return nil
+}
+
+// CleanupTimedOutBatchTxs releases all TX in every batch whose timeout height has passed back
+// into the unbatched pool, emits EventBatchTxTimedOut for each so relayers can react without
+// polling, and deletes the batch. This is the keeper-side entrypoint intended to be called from
+// the end blocker every block; wiring that ABCI call site is not part of this change, so until
+// it lands, nothing invokes this automatically.
+func (k Keeper) CleanupTimedOutBatchTxs(ctx sdk.Context) error {
+	ethereumHeight := k.GetLatestEthereumBlockHeight(ctx).EthereumBlockHeight
+
+	var timedOut []*types.BatchTx
+	k.IterateBatchTxs(ctx, func(_ []byte, batch *types.BatchTx) bool {
+		if batch.BatchTimeout < ethereumHeight {
+			timedOut = append(timedOut, batch)
+		}
+		return false
+	})
+
+	for _, batch := range timedOut {
+		for _, tx := range batch.Transactions {
+			tx.Fee.Contract = batch.TokenContract
+			k.prependToUnbatchedTXIndex(ctx, batch.TokenContract, *tx.Fee, tx.Id)
+		}
+
+		k.EmitBatchTxTimedOutEvent(ctx, batch)
+		k.DeleteBatch(ctx, *batch)
+	}
+
 	return nil
 }
 
@@ -238,18 +276,33 @@ func (k Keeper) GetBatchTxs(ctx sdk.Context) (out []*types.BatchTx) {
 	return
 }
 
-// GetLastBatchTxByTokenType gets the latest batch tx by token type
+// GetLastBatchTxByTokenType gets the latest batch tx by token type. Rather than scanning every
+// batch ever stored across every token, this opens a reverse iterator over just the token's
+// BatchTxByTokenNonceKey prefix and returns the first hit, so cost no longer grows with the
+// all-time batch count.
 func (k Keeper) GetLastBatchTxByTokenType(ctx sdk.Context, token string) *types.BatchTx {
-	batches := k.GetBatchTxs(ctx)
-	var lastBatch *types.BatchTx = nil
-	lastNonce := uint64(0)
-	for _, batch := range batches {
-		if batch.TokenContract == token && batch.BatchNonce > lastNonce {
-			lastBatch = batch
-			lastNonce = batch.BatchNonce
-		}
+	prefixStore := prefix.NewStore(ctx.KVStore(k.storeKey), types.BatchTxByTokenNoncePrefixKey(token))
+	iter := prefixStore.ReverseIterator(nil, nil)
+	defer iter.Close()
+	if !iter.Valid() {
+		return nil
+	}
+
+	var batch types.BatchTx
+	k.cdc.MustUnmarshalBinaryBare(iter.Value(), &batch)
+	return &batch
+}
+
+// getLatestBatchNonceByToken returns the nonce of the most recently stored batch for token, kept
+// up to date by indexBatch/DeleteBatch, so the "is this batch more profitable than the
+// outstanding one" check in BuildBatchTx doesn't need to open an iterator at all.
+func (k Keeper) getLatestBatchNonceByToken(ctx sdk.Context, token string) (uint64, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetLatestBatchNonceByTokenKey(token))
+	if len(bz) == 0 {
+		return 0, false
 	}
-	return lastBatch
+	return types.UInt64FromBytes(bz), true
 }
 
 // SetLastSlashedBatchBlock sets the latest slashed Batch block height
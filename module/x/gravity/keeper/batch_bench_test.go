@@ -0,0 +1,43 @@
+package keeper
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gravity-bridge/module/x/gravity/types"
+)
+
+// seedHistoricalBatches stores numTokens * batchesPerToken batches directly (bypassing
+// BuildBatchTx) to simulate a chain with a large amount of batch history.
+func seedHistoricalBatches(b *testing.B, k Keeper, ctx sdk.Context, numTokens, batchesPerToken int) []string {
+	b.Helper()
+	tokens := make([]string, numTokens)
+	for t := 0; t < numTokens; t++ {
+		token := fmt.Sprintf("0x%040d", t)
+		tokens[t] = token
+		for n := 1; n <= batchesPerToken; n++ {
+			k.StoreBatch(ctx, &types.BatchTx{
+				BatchNonce:    uint64(n),
+				TokenContract: token,
+			})
+		}
+	}
+	return tokens
+}
+
+// BenchmarkGetLastBatchTxByTokenType demonstrates that looking up the latest batch for a token
+// contract costs a single prefixed seek rather than a scan over every batch ever stored across
+// every token, by seeding thousands of historical batches across dozens of tokens.
+func BenchmarkGetLastBatchTxByTokenType(b *testing.B) {
+	input := CreateTestEnv(b)
+	ctx := input.Context
+
+	tokens := seedHistoricalBatches(b, input.GravityKeeper, ctx, 50, 2000)
+	target := tokens[len(tokens)/2]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		input.GravityKeeper.GetLastBatchTxByTokenType(ctx, target)
+	}
+}
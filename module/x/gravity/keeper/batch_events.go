@@ -0,0 +1,92 @@
+package keeper
+
+import (
+	"fmt"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/gravity-bridge/module/x/gravity/types"
+)
+
+// emitBatchTxCreatedEvent emits the typed EventBatchTxCreated for a freshly built batch, plus
+// the legacy string-attribute event for one release while downstream indexers migrate over.
+// EmitLegacyBatchEvents defaults to true via Migrator.Migrate2to3 (and must default to true in
+// types.DefaultParams for new chains) so the legacy event keeps firing until it's turned off.
+func (k Keeper) emitBatchTxCreatedEvent(ctx sdk.Context, batch *types.BatchTx) {
+	if err := ctx.EventManager().EmitTypedEvent(&types.EventBatchTxCreated{
+		BatchNonce:    batch.BatchNonce,
+		TokenContract: batch.TokenContract,
+		Block:         batch.Block,
+		TotalFees:     batch.GetFees(),
+	}); err != nil {
+		panic(err)
+	}
+
+	if k.GetParams(ctx).EmitLegacyBatchEvents {
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeBatchTx,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(types.AttributeKeyContract, k.GetBridgeContractAddress(ctx)),
+			sdk.NewAttribute(types.AttributeKeyBridgeChainID, strconv.Itoa(int(k.GetBridgeChainID(ctx)))),
+			sdk.NewAttribute(types.AttributeKeyBatchTxID, fmt.Sprint(batch.BatchNonce)),
+			sdk.NewAttribute(types.AttributeKeyNonce, fmt.Sprint(batch.BatchNonce)),
+		))
+	}
+}
+
+// emitBatchTxExecutedEvent emits the typed EventBatchTxExecuted once a batch has been confirmed
+// executed on Ethereum, so relayers and indexers can react without re-parsing string attributes.
+func (k Keeper) emitBatchTxExecutedEvent(ctx sdk.Context, batch *types.BatchTx) {
+	txIDs := make([]uint64, len(batch.Transactions))
+	for i, tx := range batch.Transactions {
+		txIDs[i] = tx.Id
+	}
+
+	if err := ctx.EventManager().EmitTypedEvent(&types.EventBatchTxExecuted{
+		BatchNonce:    batch.BatchNonce,
+		TokenContract: batch.TokenContract,
+		Block:         batch.Block,
+		TxIds:         txIDs,
+		TotalFees:     batch.GetFees(),
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// emitBatchTxCanceledEvent emits the typed EventBatchTxCanceled for a canceled batch, plus the
+// legacy string-attribute event for one release while downstream indexers migrate over.
+func (k Keeper) emitBatchTxCanceledEvent(ctx sdk.Context, tokenContract string, nonce uint64) {
+	if err := ctx.EventManager().EmitTypedEvent(&types.EventBatchTxCanceled{
+		BatchNonce:    nonce,
+		TokenContract: tokenContract,
+	}); err != nil {
+		panic(err)
+	}
+
+	if k.GetParams(ctx).EmitLegacyBatchEvents {
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeBatchTxCanceled,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(types.AttributeKeyContract, k.GetBridgeContractAddress(ctx)),
+			sdk.NewAttribute(types.AttributeKeyBridgeChainID, strconv.Itoa(int(k.GetBridgeChainID(ctx)))),
+			sdk.NewAttribute(types.AttributeKeyBatchTxID, fmt.Sprint(nonce)),
+			sdk.NewAttribute(types.AttributeKeyNonce, fmt.Sprint(nonce)),
+		))
+	}
+}
+
+// EmitBatchTxTimedOutEvent emits the typed EventBatchTxTimedOut for a batch whose timeout height
+// has passed. It is called from CleanupTimedOutBatchTxs. Wiring CleanupTimedOutBatchTxs into the
+// end blocker's ABCI loop is not part of this keeper-only change — until that call site lands,
+// timed-out batches are only swept when something in-process calls CleanupTimedOutBatchTxs
+// directly (as the accompanying test does), not automatically every block.
+func (k Keeper) EmitBatchTxTimedOutEvent(ctx sdk.Context, batch *types.BatchTx) {
+	if err := ctx.EventManager().EmitTypedEvent(&types.EventBatchTxTimedOut{
+		BatchNonce:    batch.BatchNonce,
+		TokenContract: batch.TokenContract,
+		Block:         batch.Block,
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,69 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gravity-bridge/module/x/gravity/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildBatchTxEmitsTypedEvent shows how a relayer or indexer subscribes to the typed
+// EventBatchTxCreated rather than re-parsing the legacy string-attribute event.
+func TestBuildBatchTxEmitsTypedEvent(t *testing.T) {
+	input := CreateTestEnv(t)
+	ctx := input.Context
+
+	mySender, _ := sdk.AccAddressFromBech32("cosmos1ahx7f8wyertuus9r20284ej0asrs085case3kn")
+	myTokenContractAddr := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+	amount := types.NewERC20Token(100, myTokenContractAddr).GravityCoin()
+	fee := types.NewERC20Token(2, myTokenContractAddr).GravityCoin()
+
+	require.NoError(t, input.BankKeeper.MintCoins(ctx, types.ModuleName, sdk.NewCoins(amount)))
+	input.AccountKeeper.NewAccountWithAddress(ctx, mySender)
+	require.NoError(t, input.BankKeeper.SetBalances(ctx, mySender, sdk.NewCoins(amount)))
+	_, err := input.GravityKeeper.AddToOutgoingPool(ctx, mySender, myTokenContractAddr, amount, fee)
+	require.NoError(t, err)
+
+	_, err = input.GravityKeeper.BuildBatchTx(ctx, myTokenContractAddr, 10)
+	require.NoError(t, err)
+
+	eventName := proto.MessageName(&types.EventBatchTxCreated{})
+	found := false
+	for _, event := range ctx.EventManager().ABCIEvents() {
+		if event.Type == eventName {
+			found = true
+		}
+	}
+	require.True(t, found, "expected a typed EventBatchTxCreated to have been emitted")
+}
+
+// TestCleanupTimedOutBatchTxsEmitsTypedEvent proves EmitBatchTxTimedOutEvent is actually wired
+// up: a batch whose timeout height has passed must be swept and emit EventBatchTxTimedOut.
+func TestCleanupTimedOutBatchTxsEmitsTypedEvent(t *testing.T) {
+	input := CreateTestEnv(t)
+	ctx := input.Context
+
+	tokenContract := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+	input.GravityKeeper.SetLastObservedEthereumBlockHeight(ctx, 500)
+
+	batch := &types.BatchTx{
+		BatchNonce:    1,
+		TokenContract: tokenContract,
+		BatchTimeout:  499, // already passed relative to the Ethereum height set above
+	}
+	input.GravityKeeper.StoreBatch(ctx, batch)
+
+	require.NoError(t, input.GravityKeeper.CleanupTimedOutBatchTxs(ctx))
+
+	eventName := proto.MessageName(&types.EventBatchTxTimedOut{})
+	found := false
+	for _, event := range ctx.EventManager().ABCIEvents() {
+		if event.Type == eventName {
+			found = true
+		}
+	}
+	require.True(t, found, "expected a typed EventBatchTxTimedOut to have been emitted")
+	require.Nil(t, input.GravityKeeper.GetBatchTx(ctx, tokenContract, batch.BatchNonce))
+}
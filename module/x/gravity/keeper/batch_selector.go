@@ -0,0 +1,186 @@
+package keeper
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/gravity-bridge/module/x/gravity/types"
+)
+
+// BatchSelector picks which queued SendToEthereum transactions go into the next batch for a
+// token contract, and produces a comparable score for a set of transactions that BuildBatchTx
+// uses to decide whether a new batch is worth building over the outstanding one.
+type BatchSelector interface {
+	// Select returns up to maxElements transactions from the unbatched pool for contractAddress,
+	// removing each one from the "available" second index as it's chosen.
+	Select(ctx sdk.Context, k Keeper, contractAddress string, maxElements int) ([]*types.SendToEthereum, error)
+	// Score returns a comparable value for a set of transactions, used to decide whether a
+	// hypothetical batch is worth building over an outstanding one.
+	Score(txs []*types.SendToEthereum) sdk.Int
+}
+
+// batchSelectorByStrategy returns the BatchSelector configured by the BatchSelectionStrategy
+// module param. The zero value of the enum already resolves to the greedy selector here, but
+// Migrator.Migrate1to2 additionally writes BatchSelectionStrategy_GREEDY_FEE into state
+// explicitly for chains upgrading from before this param existed, rather than relying on the
+// zero-value match alone.
+func (k Keeper) batchSelectorByStrategy(ctx sdk.Context) BatchSelector {
+	params := k.GetParams(ctx)
+	switch params.BatchSelectionStrategy {
+	case types.BatchSelectionStrategy_KNAPSACK:
+		return KnapsackBatchSelector{GasBudget: params.BatchGasBudget}
+	case types.BatchSelectionStrategy_FAIRNESS:
+		return FairnessBatchSelector{MaxTxAgeBlocks: params.MaxTxAgeBlocks}
+	default:
+		return GreedyFeeBatchSelector{}
+	}
+}
+
+// getUnbatchedTxPool returns every transaction currently queued for contractAddress without
+// removing any of them, for use in score comparisons that must not mutate the pool.
+func (k Keeper) getUnbatchedTxPool(ctx sdk.Context, contractAddress string) []*types.SendToEthereum {
+	var txs []*types.SendToEthereum
+	k.IterateSendToEthereumPoolByFee(ctx, contractAddress, func(_ uint64, tx *types.SendToEthereum) bool {
+		if tx != nil {
+			txs = append(txs, tx)
+		}
+		return false
+	})
+	return txs
+}
+
+// GreedyFeeBatchSelector is the original policy: take the maxElements highest-fee transactions.
+// It remains the default so existing chains keep their prior behavior.
+type GreedyFeeBatchSelector struct{}
+
+func (s GreedyFeeBatchSelector) Select(
+	ctx sdk.Context, k Keeper, contractAddress string, maxElements int) ([]*types.SendToEthereum, error) {
+	var selectedTx []*types.SendToEthereum
+	var err error
+	k.IterateSendToEthereumPoolByFee(ctx, contractAddress, func(txID uint64, tx *types.SendToEthereum) bool {
+		if tx != nil && tx.Fee != nil {
+			selectedTx = append(selectedTx, tx)
+			err = k.removeFromUnbatchedTXIndex(ctx, *tx.Fee, txID)
+			return err != nil || len(selectedTx) == maxElements
+		}
+		return true
+	})
+	return selectedTx, err
+}
+
+func (s GreedyFeeBatchSelector) Score(txs []*types.SendToEthereum) sdk.Int {
+	total := sdk.ZeroInt()
+	for _, tx := range txs {
+		if tx.Fee != nil {
+			total = total.Add(tx.Fee.Amount)
+		}
+	}
+	return total
+}
+
+// batchHeaderGasCost and perTransferGasCost are the fixed and per-transfer components of the
+// estimated gas weight of a batch, used by KnapsackBatchSelector to stay under GasBudget.
+const (
+	batchHeaderGasCost = uint64(100000)
+	perTransferGasCost = uint64(40000)
+)
+
+// KnapsackBatchSelector maximizes total fees subject to a per-batch gas budget, where each
+// SendToEthereum carries an estimated fixed per-transfer gas cost on top of the batch header's
+// fixed cost. It approximates the knapsack optimum with a fee-density greedy pass: transactions
+// are already visited fee-first, so the highest fee-per-gas transfers are taken until the
+// budget is exhausted, rather than always filling to maxElements.
+type KnapsackBatchSelector struct {
+	GasBudget uint64
+}
+
+func (s KnapsackBatchSelector) Select(
+	ctx sdk.Context, k Keeper, contractAddress string, maxElements int) ([]*types.SendToEthereum, error) {
+	var selectedTx []*types.SendToEthereum
+	var err error
+	remainingGas := s.gasBudget()
+	if remainingGas <= batchHeaderGasCost {
+		return nil, nil
+	}
+	remainingGas -= batchHeaderGasCost
+
+	k.IterateSendToEthereumPoolByFee(ctx, contractAddress, func(txID uint64, tx *types.SendToEthereum) bool {
+		if tx == nil || tx.Fee == nil {
+			return true
+		}
+		if perTransferGasCost > remainingGas || len(selectedTx) == maxElements {
+			return true
+		}
+		selectedTx = append(selectedTx, tx)
+		remainingGas -= perTransferGasCost
+		err = k.removeFromUnbatchedTXIndex(ctx, *tx.Fee, txID)
+		return err != nil
+	})
+	return selectedTx, err
+}
+
+func (s KnapsackBatchSelector) Score(txs []*types.SendToEthereum) sdk.Int {
+	return GreedyFeeBatchSelector{}.Score(txs)
+}
+
+func (s KnapsackBatchSelector) gasBudget() uint64 {
+	if s.GasBudget == 0 {
+		return batchHeaderGasCost + perTransferGasCost*uint64(BatchTxSize)
+	}
+	return s.GasBudget
+}
+
+// FairnessBatchSelector guarantees inclusion of every transfer older than MaxTxAgeBlocks
+// regardless of fee, so low-fee senders are never starved indefinitely, then fills any
+// remaining room fee-first like GreedyFeeBatchSelector.
+type FairnessBatchSelector struct {
+	MaxTxAgeBlocks uint64
+}
+
+func (s FairnessBatchSelector) Select(
+	ctx sdk.Context, k Keeper, contractAddress string, maxElements int) ([]*types.SendToEthereum, error) {
+	var aged, rest []*types.SendToEthereum
+	currentHeight := uint64(ctx.BlockHeight())
+	k.IterateSendToEthereumPoolByFee(ctx, contractAddress, func(_ uint64, tx *types.SendToEthereum) bool {
+		if tx == nil || tx.Fee == nil {
+			return false
+		}
+		if currentHeight > tx.Height && currentHeight-tx.Height >= s.MaxTxAgeBlocks {
+			aged = append(aged, tx)
+		} else {
+			rest = append(rest, tx)
+		}
+		return false
+	})
+
+	// oldest first: if the aged set alone overflows maxElements, the longest-waiting transfers
+	// must win the slots, not whichever happened to come first in fee order. The aged transfers
+	// bumped out this round remain aged (and keep sorting to the front) on the next BuildBatchTx
+	// call, so every transfer older than MaxTxAgeBlocks is still guaranteed to eventually batch.
+	sort.Slice(aged, func(i, j int) bool { return aged[i].Height < aged[j].Height })
+	if len(aged) > maxElements {
+		aged = aged[:maxElements]
+	}
+
+	selectedTx := aged
+	for _, tx := range rest {
+		if len(selectedTx) == maxElements {
+			break
+		}
+		selectedTx = append(selectedTx, tx)
+	}
+
+	var err error
+	for _, tx := range selectedTx {
+		if e := k.removeFromUnbatchedTXIndex(ctx, *tx.Fee, tx.Id); e != nil {
+			err = e
+			break
+		}
+	}
+	return selectedTx, err
+}
+
+func (s FairnessBatchSelector) Score(txs []*types.SendToEthereum) sdk.Int {
+	return GreedyFeeBatchSelector{}.Score(txs)
+}
@@ -0,0 +1,109 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gravity-bridge/module/x/gravity/types"
+	"github.com/stretchr/testify/require"
+)
+
+func makeSendToEthereum(id, fee uint64, height uint64) *types.SendToEthereum {
+	return &types.SendToEthereum{
+		Id:     id,
+		Height: height,
+		Fee:    &types.ERC20Token{Amount: sdk.NewInt(int64(fee))},
+	}
+}
+
+func TestGreedyFeeBatchSelectorScore(t *testing.T) {
+	selector := GreedyFeeBatchSelector{}
+	txs := []*types.SendToEthereum{
+		makeSendToEthereum(1, 5, 0),
+		makeSendToEthereum(2, 3, 0),
+	}
+	require.Equal(t, sdk.NewInt(8), selector.Score(txs))
+}
+
+func TestKnapsackBatchSelectorStaysWithinGasBudget(t *testing.T) {
+	input := CreateTestEnv(t)
+	ctx := input.Context
+
+	// a budget for only 2 transfers, with 5 available and a maxElements well above that
+	selector := KnapsackBatchSelector{GasBudget: batchHeaderGasCost + perTransferGasCost*2}
+	contractAddress := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+
+	mySender, _ := sdk.AccAddressFromBech32("cosmos1ahx7f8wyertuus9r20284ej0asrs085case3kn")
+	input.AccountKeeper.NewAccountWithAddress(ctx, mySender)
+
+	for i := 0; i < 5; i++ {
+		amount := types.NewERC20Token(uint64(100+i), contractAddress).GravityCoin()
+		fee := types.NewERC20Token(uint64(i+1), contractAddress).GravityCoin()
+		require.NoError(t, input.BankKeeper.MintCoins(ctx, types.ModuleName, sdk.NewCoins(amount)))
+		require.NoError(t, input.BankKeeper.SetBalances(ctx, mySender, sdk.NewCoins(amount)))
+		_, err := input.GravityKeeper.AddToOutgoingPool(ctx, mySender, contractAddress, amount, fee)
+		require.NoError(t, err)
+	}
+
+	selected, err := selector.Select(ctx, input.GravityKeeper, contractAddress, 10)
+	require.NoError(t, err)
+	require.Len(t, selected, 2, "selector must stop at the gas budget, not maxElements")
+}
+
+func TestFairnessBatchSelectorIncludesAgedTransfersRegardlessOfFee(t *testing.T) {
+	input := CreateTestEnv(t)
+	ctx := input.Context.WithBlockHeight(1000)
+
+	selector := FairnessBatchSelector{MaxTxAgeBlocks: 100}
+	contractAddress := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+
+	mySender, _ := sdk.AccAddressFromBech32("cosmos1ahx7f8wyertuus9r20284ej0asrs085case3kn")
+	input.AccountKeeper.NewAccountWithAddress(ctx, mySender)
+
+	oldCtx := input.Context.WithBlockHeight(1)
+	lowFee := types.NewERC20Token(1, contractAddress).GravityCoin()
+	amount := types.NewERC20Token(100, contractAddress).GravityCoin()
+	require.NoError(t, input.BankKeeper.MintCoins(oldCtx, types.ModuleName, sdk.NewCoins(amount)))
+	require.NoError(t, input.BankKeeper.SetBalances(oldCtx, mySender, sdk.NewCoins(amount)))
+	_, err := input.GravityKeeper.AddToOutgoingPool(oldCtx, mySender, contractAddress, amount, lowFee)
+	require.NoError(t, err)
+
+	selected, err := selector.Select(ctx, input.GravityKeeper, contractAddress, 10)
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+}
+
+// TestFairnessBatchSelectorOldestAgedWinsWhenAgedOverflowsMaxElements proves that when the
+// number of aged (>= MaxTxAgeBlocks) transfers itself exceeds maxElements, the selector keeps
+// the oldest ones rather than silently dropping some of the transfers it exists to protect.
+func TestFairnessBatchSelectorOldestAgedWinsWhenAgedOverflowsMaxElements(t *testing.T) {
+	input := CreateTestEnv(t)
+	ctx := input.Context.WithBlockHeight(1000)
+
+	selector := FairnessBatchSelector{MaxTxAgeBlocks: 100}
+	contractAddress := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+
+	mySender, _ := sdk.AccAddressFromBech32("cosmos1ahx7f8wyertuus9r20284ej0asrs085case3kn")
+	input.AccountKeeper.NewAccountWithAddress(ctx, mySender)
+
+	// 3 aged transfers at different heights, but maxElements only has room for 2: the oldest
+	// (height 1) and next-oldest (height 2) must win over the most-recently-aged (height 3)
+	var oldestFee sdk.Coin
+	for i, height := range []int64{1, 2, 3} {
+		txCtx := input.Context.WithBlockHeight(height)
+		amount := types.NewERC20Token(uint64(100+i), contractAddress).GravityCoin()
+		fee := types.NewERC20Token(uint64(i+1), contractAddress).GravityCoin()
+		if height == 1 {
+			oldestFee = fee
+		}
+		require.NoError(t, input.BankKeeper.MintCoins(txCtx, types.ModuleName, sdk.NewCoins(amount)))
+		require.NoError(t, input.BankKeeper.SetBalances(txCtx, mySender, sdk.NewCoins(amount)))
+		_, err := input.GravityKeeper.AddToOutgoingPool(txCtx, mySender, contractAddress, amount, fee)
+		require.NoError(t, err)
+	}
+
+	selected, err := selector.Select(ctx, input.GravityKeeper, contractAddress, 2)
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	require.Equal(t, oldestFee.Amount, selected[0].Fee.Amount, "the oldest aged transfer must survive the overflow")
+}
@@ -0,0 +1,319 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cosmos/gravity-bridge/module/x/gravity/types"
+)
+
+// This file is keeper-only plumbing for the bridge migration feature: the Msg types and message
+// handler that let governance propose a migration and let orchestrators submit
+// RecordMigrationBalanceVote votes from the outside are explicit follow-up work, not part of this
+// change. Nothing outside this package's tests calls BuildMigrationBatchTx,
+// RecordMigrationBalanceVote, or MigrationBatchTxExecuted yet.
+//
+// BuildMigrationBatchTx constructs a MigrationBatchTx that sweeps the full balance of the given
+// token contracts held by oldContract to newContract. This is used when governance approves an
+// Ethereum-side contract upgrade: orchestrators observe oldContract's balance of each token and
+// vote it in via RecordMigrationBalanceVote, and once their combined power crosses quorum this
+// builds a signed batch instructing validators to call transferToNewSafe(newContract, token,
+// amount) for each token, rather than draining from the SendToEthereum pool like an ordinary
+// BatchTx.
+//
+// It marks every affected token contract as migration-in-progress so BuildBatchTx refuses to
+// build ordinary batches against them until this migration batch is executed or canceled.
+func (k Keeper) BuildMigrationBatchTx(
+	ctx sdk.Context,
+	oldContract string,
+	newContract string,
+	tokenContracts []string) (*types.MigrationBatchTx, error) {
+	if oldContract == "" || newContract == "" {
+		return nil, sdkerrors.Wrap(types.ErrInvalid, "old and new contract address")
+	}
+	if len(tokenContracts) == 0 {
+		return nil, sdkerrors.Wrap(types.ErrInvalid, "no tokens to migrate")
+	}
+
+	amounts := make([]*types.MigrationTokenAmount, 0, len(tokenContracts))
+	for _, token := range tokenContracts {
+		if k.GetMigrationInProgress(ctx, token) {
+			return nil, sdkerrors.Wrapf(types.ErrInvalid, "migration already in progress for %s", token)
+		}
+		amount := k.GetMigrationVotedAmount(ctx, oldContract, token)
+		if amount == nil {
+			return nil, sdkerrors.Wrapf(types.ErrInvalid, "no quorum balance vote for %s on %s", token, oldContract)
+		}
+		amounts = append(amounts, amount)
+	}
+
+	nextID := k.autoIncrementID(ctx, types.KeyLastMigrationBatchTxID)
+	batch := &types.MigrationBatchTx{
+		BatchNonce:  nextID,
+		OldContract: oldContract,
+		NewContract: newContract,
+		Amounts:     amounts,
+		Timeout:     k.getBatchTimeoutHeight(ctx),
+	}
+	k.StoreMigrationBatch(ctx, batch)
+
+	for _, token := range tokenContracts {
+		k.SetMigrationInProgress(ctx, token)
+	}
+
+	// migration checkpoints are domain-separated with their own method identifier
+	// (transferToNewSafe rather than transactionBatch) so CheckBadSignatureEvidence can
+	// tell a migration batch's checkpoint apart from an ordinary BatchTx's
+	checkpoint := batch.GetCheckpoint(k.GetGravityID(ctx))
+	k.SetPastEthSignatureCheckpoint(ctx, checkpoint)
+
+	migrationEvent := sdk.NewEvent(
+		types.EventTypeMigrationBatchTx,
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+		sdk.NewAttribute(types.AttributeKeyContract, oldContract),
+		sdk.NewAttribute(types.AttributeKeyBridgeChainID, fmt.Sprint(k.GetBridgeChainID(ctx))),
+		sdk.NewAttribute(types.AttributeKeyNonce, fmt.Sprint(nextID)),
+	)
+	ctx.EventManager().EmitEvent(migrationEvent)
+
+	return batch, nil
+}
+
+// MigrationBatchTxExecuted is run when the Cosmos chain detects that a migration batch's
+// transferToNewSafe calls have executed on Ethereum. It mirrors BatchTxExecuted: it deletes the
+// migration batch and, via DeleteMigrationBatch, lifts the migration-in-progress guard on every
+// token contract the batch covered so BuildBatchTx can resume building ordinary batches for them.
+func (k Keeper) MigrationBatchTxExecuted(ctx sdk.Context, oldContract string, nonce uint64) error {
+	batch := k.GetMigrationBatchTx(ctx, oldContract, nonce)
+	if batch == nil {
+		return sdkerrors.Wrap(types.ErrUnknown, "nonce")
+	}
+
+	k.DeleteMigrationBatch(ctx, *batch)
+	return nil
+}
+
+// StoreMigrationBatch stores a migration batch tx. Migration batches live in their own nonce
+// space (KeyLastMigrationBatchTxID) and store prefix, keyed by the old contract being migrated
+// away from, so they never collide with ordinary per-token BatchTx nonces.
+func (k Keeper) StoreMigrationBatch(ctx sdk.Context, batch *types.MigrationBatchTx) {
+	store := ctx.KVStore(k.storeKey)
+	batch.Block = uint64(ctx.BlockHeight())
+	key := types.GetMigrationBatchTxKey(batch.OldContract, batch.BatchNonce)
+	store.Set(key, k.cdc.MustMarshalBinaryBare(batch))
+}
+
+// GetMigrationBatchTx loads a migration batch object. Returns nil when not exists.
+func (k Keeper) GetMigrationBatchTx(ctx sdk.Context, oldContract string, nonce uint64) *types.MigrationBatchTx {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetMigrationBatchTxKey(oldContract, nonce))
+	if len(bz) == 0 {
+		return nil
+	}
+	var b types.MigrationBatchTx
+	k.cdc.MustUnmarshalBinaryBare(bz, &b)
+	return &b
+}
+
+// DeleteMigrationBatch deletes a migration batch tx and lifts the migration-in-progress guard
+// for every token contract it covered, once the migration has executed on Ethereum.
+func (k Keeper) DeleteMigrationBatch(ctx sdk.Context, batch types.MigrationBatchTx) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetMigrationBatchTxKey(batch.OldContract, batch.BatchNonce))
+	for _, amount := range batch.Amounts {
+		k.ClearMigrationInProgress(ctx, amount.TokenContract)
+	}
+}
+
+// CancelMigrationBatchTx cancels an outstanding migration batch and lifts the
+// migration-in-progress guard on every token contract it covered, mirroring CancelBatchTx. Unlike
+// an ordinary batch, a migration batch's transfers never came out of the SendToEthereum pool, so
+// there is nothing to release back into it; canceling just abandons the batch and its checkpoint.
+func (k Keeper) CancelMigrationBatchTx(ctx sdk.Context, oldContract string, nonce uint64) error {
+	batch := k.GetMigrationBatchTx(ctx, oldContract, nonce)
+	if batch == nil {
+		return types.ErrUnknown
+	}
+
+	k.DeleteMigrationBatch(ctx, *batch)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeMigrationBatchTxCanceled,
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+		sdk.NewAttribute(types.AttributeKeyContract, oldContract),
+		sdk.NewAttribute(types.AttributeKeyNonce, fmt.Sprint(nonce)),
+	))
+	return nil
+}
+
+// CleanupTimedOutMigrationBatchTxs lifts the migration-in-progress guard and deletes every
+// migration batch whose timeout height has passed, mirroring CleanupTimedOutBatchTxs. As with
+// that entrypoint, wiring this into the end blocker's ABCI loop is not part of this change; until
+// a call site lands, a timed-out migration batch only clears when something in-process calls
+// this directly (as the accompanying test does), not automatically every block.
+func (k Keeper) CleanupTimedOutMigrationBatchTxs(ctx sdk.Context) error {
+	ethereumHeight := k.GetLatestEthereumBlockHeight(ctx).EthereumBlockHeight
+
+	var timedOut []*types.MigrationBatchTx
+	k.IterateMigrationBatchTxs(ctx, func(_ []byte, batch *types.MigrationBatchTx) bool {
+		if batch.Timeout < ethereumHeight {
+			timedOut = append(timedOut, batch)
+		}
+		return false
+	})
+
+	for _, batch := range timedOut {
+		k.DeleteMigrationBatch(ctx, *batch)
+
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeMigrationBatchTxTimedOut,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(types.AttributeKeyContract, batch.OldContract),
+			sdk.NewAttribute(types.AttributeKeyNonce, fmt.Sprint(batch.BatchNonce)),
+		))
+	}
+
+	return nil
+}
+
+// IterateMigrationBatchTxs iterates through all migration batch txs in DESC order.
+func (k Keeper) IterateMigrationBatchTxs(ctx sdk.Context, cb func(key []byte, batch *types.MigrationBatchTx) bool) {
+	prefixStore := prefix.NewStore(ctx.KVStore(k.storeKey), types.MigrationBatchTxKey)
+	iter := prefixStore.ReverseIterator(nil, nil)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var batch types.MigrationBatchTx
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &batch)
+		// cb returns true to stop early
+		if cb(iter.Key(), &batch) {
+			break
+		}
+	}
+}
+
+// SetMigrationInProgress marks tokenContract as having an outstanding migration batch.
+func (k Keeper) SetMigrationInProgress(ctx sdk.Context, tokenContract string) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetMigrationInProgressKey(tokenContract), []byte{1})
+}
+
+// ClearMigrationInProgress lifts the migration-in-progress guard for tokenContract.
+func (k Keeper) ClearMigrationInProgress(ctx sdk.Context, tokenContract string) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetMigrationInProgressKey(tokenContract))
+}
+
+// GetMigrationInProgress returns true if tokenContract currently has an outstanding migration
+// batch, meaning BuildBatchTx must refuse to build an ordinary batch against it.
+func (k Keeper) GetMigrationInProgress(ctx sdk.Context, tokenContract string) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(types.GetMigrationInProgressKey(tokenContract))
+}
+
+// GetMigrationVotedAmount returns the quorum-agreed balance of token on oldContract, finalized
+// once RecordMigrationBalanceVote has crossed quorum, or nil if quorum has not yet been reached.
+func (k Keeper) GetMigrationVotedAmount(ctx sdk.Context, oldContract string, token string) *types.MigrationTokenAmount {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetMigrationVotedAmountKey(oldContract, token))
+	if len(bz) == 0 {
+		return nil
+	}
+	var amount types.MigrationTokenAmount
+	k.cdc.MustUnmarshalBinaryBare(bz, &amount)
+	return &amount
+}
+
+// setMigrationVotedAmount finalizes the quorum-agreed balance of token on oldContract once
+// RecordMigrationBalanceVote has crossed quorum. Unexported: callers must go through
+// RecordMigrationBalanceVote rather than setting an amount directly.
+func (k Keeper) setMigrationVotedAmount(ctx sdk.Context, oldContract string, amount types.MigrationTokenAmount) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetMigrationVotedAmountKey(oldContract, amount.TokenContract), k.cdc.MustMarshalBinaryBare(&amount))
+}
+
+// getMigrationBalanceAttestation loads the in-progress vote tally for a (oldContract, token,
+// amount) balance claim, or a fresh one with no votes if none exists yet.
+func (k Keeper) getMigrationBalanceAttestation(
+	ctx sdk.Context, oldContract string, token string, amount sdk.Int) types.MigrationBalanceAttestation {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetMigrationBalanceAttestationKey(oldContract, token, amount))
+	if len(bz) == 0 {
+		return types.MigrationBalanceAttestation{
+			TokenContract: token,
+			Amount:        amount,
+		}
+	}
+	var att types.MigrationBalanceAttestation
+	k.cdc.MustUnmarshalBinaryBare(bz, &att)
+	return att
+}
+
+func (k Keeper) setMigrationBalanceAttestation(
+	ctx sdk.Context, oldContract string, att types.MigrationBalanceAttestation) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetMigrationBalanceAttestationKey(oldContract, att.TokenContract, att.Amount),
+		k.cdc.MustMarshalBinaryBare(&att))
+}
+
+// RecordMigrationBalanceVote records that the validator bonded to orchestrator observed token's
+// balance on oldContract to be amount, the same EventVote-driven, power-weighted quorum mechanism
+// the module already uses to attest other Ethereum claim types (deposits, valset updates, etc.):
+// each vote accrues the submitting validator's bonded power onto the specific (token, amount)
+// tuple it voted for, and once the accrued power crosses the module's
+// AttestationVotesPowerThreshold of total bonded power, the amount is finalized via
+// setMigrationVotedAmount so BuildMigrationBatchTx can use it. A validator voting twice for the
+// same claim, or voting after quorum already finalized, is a no-op rather than an error,
+// mirroring the idempotency of the module's other attestation flows.
+//
+// orchestrator is resolved to its bonded validator via GetOrchestratorValidator rather than
+// taking a validator address directly, so a caller can only cast a vote on behalf of the
+// validator that actually delegated to it — the same binding the module's other EventVote claims
+// rely on to stop one orchestrator from voting as a validator it doesn't represent.
+func (k Keeper) RecordMigrationBalanceVote(
+	ctx sdk.Context,
+	oldContract string,
+	token string,
+	amount sdk.Int,
+	orchestrator sdk.AccAddress) error {
+	if k.GetMigrationVotedAmount(ctx, oldContract, token) != nil {
+		return nil
+	}
+
+	validator, found := k.GetOrchestratorValidator(ctx, orchestrator)
+	if !found {
+		return sdkerrors.Wrap(types.ErrUnknown, "orchestrator has no bonded validator")
+	}
+
+	att := k.getMigrationBalanceAttestation(ctx, oldContract, token, amount)
+	for _, voter := range att.Votes {
+		if voter == validator.String() {
+			return nil
+		}
+	}
+	att.Votes = append(att.Votes, validator.String())
+	k.setMigrationBalanceAttestation(ctx, oldContract, att)
+
+	votedPower := int64(0)
+	for _, voter := range att.Votes {
+		valAddr, err := sdk.ValAddressFromBech32(voter)
+		if err != nil {
+			continue
+		}
+		votedPower += k.StakingKeeper.GetLastValidatorPower(ctx, valAddr)
+	}
+
+	totalPower := k.StakingKeeper.GetLastTotalPower(ctx)
+	threshold := totalPower.MulRaw(int64(k.GetParams(ctx).AttestationVotesPowerThreshold)).QuoRaw(100)
+	if sdk.NewInt(votedPower).LT(threshold) {
+		return nil
+	}
+
+	k.setMigrationVotedAmount(ctx, oldContract, types.MigrationTokenAmount{
+		TokenContract: token,
+		Amount:        amount,
+	})
+	return nil
+}
@@ -0,0 +1,129 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMigrationBatchTxBlocksOrdinaryBatchUntilExecuted(t *testing.T) {
+	input, ctx := SetupFiveValChain(t)
+
+	oldContract := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+	newContract := "0x9FC9C2DfBA3b6cF204C37a5F690619772b926e39"
+	token := oldContract
+	amount := sdk.NewInt(1000000)
+
+	for _, val := range ValAddrs[:4] {
+		require.NoError(t, input.GravityKeeper.RecordMigrationBalanceVote(ctx, oldContract, token, amount, sdk.AccAddress(val)))
+	}
+	require.NotNil(t, input.GravityKeeper.GetMigrationVotedAmount(ctx, oldContract, token))
+
+	batch, err := input.GravityKeeper.BuildMigrationBatchTx(ctx, oldContract, newContract, []string{token})
+	require.NoError(t, err)
+	require.True(t, input.GravityKeeper.GetMigrationInProgress(ctx, token))
+
+	// BuildBatchTx must refuse to build an ordinary batch while the migration is outstanding
+	_, err = input.GravityKeeper.BuildBatchTx(ctx, token, 10)
+	require.Error(t, err)
+
+	// once Ethereum confirms the migration batch executed, the guard must lift
+	require.NoError(t, input.GravityKeeper.MigrationBatchTxExecuted(ctx, oldContract, batch.BatchNonce))
+	require.False(t, input.GravityKeeper.GetMigrationInProgress(ctx, token))
+	require.Nil(t, input.GravityKeeper.GetMigrationBatchTx(ctx, oldContract, batch.BatchNonce))
+}
+
+func TestBuildMigrationBatchTxRequiresVotedAmount(t *testing.T) {
+	input := CreateTestEnv(t)
+	ctx := input.Context
+
+	_, err := input.GravityKeeper.BuildMigrationBatchTx(ctx, "0xOldContract", "0xNewContract", []string{"0xTokenContract"})
+	require.Error(t, err)
+}
+
+func TestRecordMigrationBalanceVoteRequiresQuorumPower(t *testing.T) {
+	input, ctx := SetupFiveValChain(t)
+
+	oldContract := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+	token := oldContract
+	amount := sdk.NewInt(1000000)
+
+	// a minority of validator power is not enough to finalize the amount
+	for _, val := range ValAddrs[:2] {
+		require.NoError(t, input.GravityKeeper.RecordMigrationBalanceVote(ctx, oldContract, token, amount, sdk.AccAddress(val)))
+	}
+	require.Nil(t, input.GravityKeeper.GetMigrationVotedAmount(ctx, oldContract, token))
+
+	_, err := input.GravityKeeper.BuildMigrationBatchTx(ctx, oldContract, "0xNewContract", []string{token})
+	require.Error(t, err)
+}
+
+func TestRecordMigrationBalanceVoteRejectsUnknownOrchestrator(t *testing.T) {
+	input, ctx := SetupFiveValChain(t)
+
+	oldContract := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+	token := oldContract
+	amount := sdk.NewInt(1000000)
+
+	unknownOrchestrator := sdk.AccAddress("not-a-bonded-orchestrator")
+	require.Error(t, input.GravityKeeper.RecordMigrationBalanceVote(ctx, oldContract, token, amount, unknownOrchestrator))
+}
+
+func TestCancelMigrationBatchTxLiftsInProgressGuard(t *testing.T) {
+	input, ctx := SetupFiveValChain(t)
+
+	oldContract := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+	newContract := "0x9FC9C2DfBA3b6cF204C37a5F690619772b926e39"
+	token := oldContract
+	amount := sdk.NewInt(1000000)
+
+	for _, val := range ValAddrs[:4] {
+		require.NoError(t, input.GravityKeeper.RecordMigrationBalanceVote(ctx, oldContract, token, amount, sdk.AccAddress(val)))
+	}
+	batch, err := input.GravityKeeper.BuildMigrationBatchTx(ctx, oldContract, newContract, []string{token})
+	require.NoError(t, err)
+	require.True(t, input.GravityKeeper.GetMigrationInProgress(ctx, token))
+
+	require.NoError(t, input.GravityKeeper.CancelMigrationBatchTx(ctx, oldContract, batch.BatchNonce))
+	require.False(t, input.GravityKeeper.GetMigrationInProgress(ctx, token))
+	require.Nil(t, input.GravityKeeper.GetMigrationBatchTx(ctx, oldContract, batch.BatchNonce))
+
+	// canceling a nonce that doesn't exist is an error, same as CancelBatchTx
+	require.Error(t, input.GravityKeeper.CancelMigrationBatchTx(ctx, oldContract, batch.BatchNonce))
+}
+
+func TestCleanupTimedOutMigrationBatchTxsLiftsInProgressGuard(t *testing.T) {
+	input, ctx := SetupFiveValChain(t)
+
+	oldContract := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+	newContract := "0x9FC9C2DfBA3b6cF204C37a5F690619772b926e39"
+	token := oldContract
+	amount := sdk.NewInt(1000000)
+
+	for _, val := range ValAddrs[:4] {
+		require.NoError(t, input.GravityKeeper.RecordMigrationBalanceVote(ctx, oldContract, token, amount, sdk.AccAddress(val)))
+	}
+	batch, err := input.GravityKeeper.BuildMigrationBatchTx(ctx, oldContract, newContract, []string{token})
+	require.NoError(t, err)
+
+	input.GravityKeeper.SetLastObservedEthereumBlockHeight(ctx, batch.Timeout+1)
+
+	require.NoError(t, input.GravityKeeper.CleanupTimedOutMigrationBatchTxs(ctx))
+	require.False(t, input.GravityKeeper.GetMigrationInProgress(ctx, token))
+	require.Nil(t, input.GravityKeeper.GetMigrationBatchTx(ctx, oldContract, batch.BatchNonce))
+}
+
+func TestRecordMigrationBalanceVoteIgnoresDuplicateVote(t *testing.T) {
+	input, ctx := SetupFiveValChain(t)
+
+	oldContract := "0x429881672B9AE42b8EbA0E26cD9C73711b891Ca5"
+	token := oldContract
+	amount := sdk.NewInt(1000000)
+
+	require.NoError(t, input.GravityKeeper.RecordMigrationBalanceVote(ctx, oldContract, token, amount, sdk.AccAddress(ValAddrs[0])))
+	require.NoError(t, input.GravityKeeper.RecordMigrationBalanceVote(ctx, oldContract, token, amount, sdk.AccAddress(ValAddrs[0])))
+
+	att := input.GravityKeeper.getMigrationBalanceAttestation(ctx, oldContract, token, amount)
+	require.Len(t, att.Votes, 1)
+}
@@ -0,0 +1,45 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/gravity-bridge/module/x/gravity/types"
+)
+
+// Migrator is the in-place store migrator for the gravity module, following the standard
+// cosmos-sdk module migration pattern. Each Migrate<N>to<N+1> method must be wired up via
+// cfg.RegisterMigration(types.ModuleName, N, m.MigrateNtoN1) in the module's RegisterServices.
+type Migrator struct {
+	keeper Keeper
+}
+
+// NewMigrator returns a new Migrator for the gravity module.
+func NewMigrator(k Keeper) Migrator {
+	return Migrator{keeper: k}
+}
+
+// Migrate1to2 explicitly sets BatchSelectionStrategy on existing chains' stored params to
+// BatchSelectionStrategy_GREEDY_FEE. Stored params predating this field don't contain it, and
+// while the zero value of the enum already resolves to the greedy selector in
+// batchSelectorByStrategy, that's a runtime default, not something recorded in state — querying
+// Params before this migration runs would show an unset/zero field rather than the behavior
+// chains are actually relying on. This migration makes it explicit in state.
+func (m Migrator) Migrate1to2(ctx sdk.Context) error {
+	params := m.keeper.GetParams(ctx)
+	params.BatchSelectionStrategy = types.BatchSelectionStrategy_GREEDY_FEE
+	m.keeper.SetParams(ctx, params)
+	return nil
+}
+
+// Migrate2to3 explicitly sets EmitLegacyBatchEvents on existing chains' stored params to true.
+// The legacy batch events were meant to stay on by default for one release while downstream
+// indexers migrate to the typed events, but a bool param predating this field decodes to its
+// zero value, false, on a chain that upgrades without this migration — silently turning legacy
+// events off on day one instead of after the deprecation window. This migration makes the
+// intended default explicit in state, the same way Migrate1to2 does for BatchSelectionStrategy.
+func (m Migrator) Migrate2to3(ctx sdk.Context) error {
+	params := m.keeper.GetParams(ctx)
+	params.EmitLegacyBatchEvents = true
+	m.keeper.SetParams(ctx, params)
+	return nil
+}
@@ -0,0 +1,34 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/cosmos/gravity-bridge/module/x/gravity/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate1to2SetsGreedyFeeStrategyExplicitly(t *testing.T) {
+	input := CreateTestEnv(t)
+	ctx := input.Context
+
+	params := input.GravityKeeper.GetParams(ctx)
+	require.Equal(t, types.BatchSelectionStrategy_UNSPECIFIED, params.BatchSelectionStrategy)
+
+	require.NoError(t, NewMigrator(input.GravityKeeper).Migrate1to2(ctx))
+
+	params = input.GravityKeeper.GetParams(ctx)
+	require.Equal(t, types.BatchSelectionStrategy_GREEDY_FEE, params.BatchSelectionStrategy)
+}
+
+func TestMigrate2to3SetsEmitLegacyBatchEventsExplicitly(t *testing.T) {
+	input := CreateTestEnv(t)
+	ctx := input.Context
+
+	params := input.GravityKeeper.GetParams(ctx)
+	require.False(t, params.EmitLegacyBatchEvents, "unset param decodes to its zero value, false")
+
+	require.NoError(t, NewMigrator(input.GravityKeeper).Migrate2to3(ctx))
+
+	params = input.GravityKeeper.GetParams(ctx)
+	require.True(t, params.EmitLegacyBatchEvents)
+}